@@ -0,0 +1,128 @@
+// Command ldctl is a small operator CLI for ConfigService, the
+// runtime-tunable configuration RPC exposed by the example language
+// detection server, so deployments can be retuned without a restart.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/Hovhannesmn/ld_proto/pb"
+)
+
+func main() {
+	if len(os.Args) < 3 || os.Args[1] != "config" {
+		usage()
+	}
+
+	switch os.Args[2] {
+	case "get":
+		runGet(os.Args[3:])
+	case "update":
+		runUpdate(os.Args[3:])
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: ldctl config get [--addr=host:port]")
+	fmt.Fprintln(os.Stderr, "       ldctl config update [--addr=host:port] [--enabled-langs=en,es,fr] [--confidence-threshold=0.3]")
+	os.Exit(2)
+}
+
+func dial(addr string) (*grpc.ClientConn, error) {
+	return grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+}
+
+func runGet(args []string) {
+	fs := flag.NewFlagSet("config get", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:50051", "language detection server address")
+	fs.Parse(args)
+
+	conn, err := dial(*addr)
+	if err != nil {
+		log.Fatalf("dialing %s: %v", *addr, err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := pb.NewConfigServiceClient(conn).GetConfig(ctx, &pb.GetConfigRequest{})
+	if err != nil {
+		log.Fatalf("get config: %v", err)
+	}
+
+	var cfg pb.DetectionConfig
+	if err := resp.Config.UnmarshalTo(&cfg); err != nil {
+		log.Fatalf("unmarshaling config: %v", err)
+	}
+
+	printConfig(resp.Revision, &cfg)
+}
+
+func runUpdate(args []string) {
+	fs := flag.NewFlagSet("config update", flag.ExitOnError)
+	addr := fs.String("addr", "localhost:50051", "language detection server address")
+	enabledLangs := fs.String("enabled-langs", "", "comma-separated list of enabled language codes")
+	confidenceThreshold := fs.Float64("confidence-threshold", -1, "confidence threshold below which alternatives are returned")
+	fs.Parse(args)
+
+	conn, err := dial(*addr)
+	if err != nil {
+		log.Fatalf("dialing %s: %v", *addr, err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := pb.NewConfigServiceClient(conn)
+
+	current, err := client.GetConfig(ctx, &pb.GetConfigRequest{})
+	if err != nil {
+		log.Fatalf("get config: %v", err)
+	}
+
+	var cfg pb.DetectionConfig
+	if err := current.Config.UnmarshalTo(&cfg); err != nil {
+		log.Fatalf("unmarshaling config: %v", err)
+	}
+
+	if *enabledLangs != "" {
+		cfg.EnabledLanguages = strings.Split(*enabledLangs, ",")
+	}
+	if *confidenceThreshold >= 0 {
+		cfg.ConfidenceThreshold = float32(*confidenceThreshold)
+	}
+
+	next, err := anypb.New(&cfg)
+	if err != nil {
+		log.Fatalf("marshaling config: %v", err)
+	}
+
+	resp, err := client.UpdateConfig(ctx, &pb.UpdateConfigRequest{Config: next, Revision: current.Revision})
+	if err != nil {
+		log.Fatalf("update config: %v", err)
+	}
+
+	printConfig(resp.Revision, &cfg)
+}
+
+func printConfig(revision int64, cfg *pb.DetectionConfig) {
+	fmt.Printf("revision: %d\n", revision)
+	fmt.Printf("min_text_length: %d\n", cfg.MinTextLength)
+	fmt.Printf("confidence_threshold: %.2f\n", cfg.ConfidenceThreshold)
+	fmt.Printf("enabled_languages: %s\n", strings.Join(cfg.EnabledLanguages, ","))
+	fmt.Printf("provider: %s\n", cfg.Provider)
+}
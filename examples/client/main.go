@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"log"
 	"time"
 
@@ -63,6 +65,10 @@ func main() {
 			log.Printf("Service version: %s", resp.Metadata.ServiceVersion)
 		}
 		
+		if resp.LocalizedMessage != "" {
+			log.Printf("Message: %s", resp.LocalizedMessage)
+		}
+
 		if len(resp.Alternatives) > 0 {
 			log.Printf("Alternatives:")
 			for i, alt := range resp.Alternatives {
@@ -74,4 +80,62 @@ func main() {
 		}
 		log.Println("---")
 	}
+
+	// The bidirectional-streaming RPC replaces the per-document unary
+	// loop above for bulk ingestion: all documents go out over one
+	// connection instead of one round-trip each, and responses can come
+	// back in a different order than they were sent.
+	streamTestCases(client, testCases)
+}
+
+func streamTestCases(client pb.LanguageDetectionServiceClient, testCases []struct {
+	text     string
+	expected string
+}) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := client.DetectLanguageStream(ctx)
+	if err != nil {
+		log.Printf("Failed to open DetectLanguageStream: %v", err)
+		return
+	}
+
+	sendErrCh := make(chan error, 1)
+	go func() {
+		for i, tc := range testCases {
+			req := &pb.DetectLanguageRequest{
+				Text:       tc.text,
+				DocumentId: fmt.Sprintf("stream-doc-%d", i),
+				Metadata: map[string]string{
+					"source": "example_client",
+				},
+			}
+			if err := stream.Send(req); err != nil {
+				sendErrCh <- err
+				return
+			}
+		}
+		sendErrCh <- stream.CloseSend()
+	}()
+
+	log.Println("=== Streaming demo ===")
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Printf("DetectLanguageStream failed: %v", err)
+			return
+		}
+		log.Printf("Document: %s, detected language: %s (confidence: %.2f)", resp.DocumentId, resp.LanguageCode, resp.Confidence)
+		if resp.LocalizedMessage != "" {
+			log.Printf("Message: %s", resp.LocalizedMessage)
+		}
+	}
+
+	if err := <-sendErrCh; err != nil {
+		log.Printf("Sending documents failed: %v", err)
+	}
 }
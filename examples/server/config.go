@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/Hovhannesmn/ld_proto/pb"
+)
+
+// configServer implements pb.ConfigServiceServer over an in-memory
+// pb.DetectionConfig. Config is persisted as a protobuf Any so new fields
+// can be added without a breaking wire change, and Revision gives
+// UpdateConfig optimistic concurrency: a write whose Revision doesn't
+// match the currently stored one is rejected rather than silently
+// clobbering a concurrent change. Every accepted update hot-swaps the
+// detector's enabled language set.
+type configServer struct {
+	pb.UnimplementedConfigServiceServer
+
+	detector *detectorHolder
+	backend  *backendHolder
+	profiles map[string]*Profile
+
+	mu       sync.Mutex
+	config   *pb.DetectionConfig
+	revision int64
+	watchers map[chan *pb.DetectionConfig]struct{}
+}
+
+func newConfigServer(detector *detectorHolder, backend *backendHolder, profiles map[string]*Profile, initial *pb.DetectionConfig) *configServer {
+	return &configServer{
+		detector: detector,
+		backend:  backend,
+		profiles: profiles,
+		config:   initial,
+		revision: 1,
+		watchers: make(map[chan *pb.DetectionConfig]struct{}),
+	}
+}
+
+// currentConfig returns the live config, so RPC handlers elsewhere in the
+// server can read MinTextLength/ConfidenceThreshold on every call instead
+// of gating on values baked in at startup.
+func (s *configServer) currentConfig() *pb.DetectionConfig {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.config
+}
+
+// defaultDetectionConfig is the configuration the example server starts
+// with: all bundled profiles enabled, and the same confidence threshold
+// used to decide when DetectLanguage attaches a localized explanation.
+func defaultDetectionConfig() *pb.DetectionConfig {
+	return &pb.DetectionConfig{
+		MinTextLength:       1,
+		ConfidenceThreshold: lowConfidenceThreshold,
+		Provider:            "ld_proto_example",
+	}
+}
+
+func (s *configServer) GetConfig(ctx context.Context, req *pb.GetConfigRequest) (*pb.GetConfigResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cfg, err := anypb.New(s.config)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "marshaling config: %v", err)
+	}
+	return &pb.GetConfigResponse{Config: cfg, Revision: s.revision}, nil
+}
+
+func (s *configServer) UpdateConfig(ctx context.Context, req *pb.UpdateConfigRequest) (*pb.UpdateConfigResponse, error) {
+	var next pb.DetectionConfig
+	if err := req.Config.UnmarshalTo(&next); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unmarshaling config: %v", err)
+	}
+
+	s.mu.Lock()
+	if req.Revision != s.revision {
+		s.mu.Unlock()
+		return nil, status.Errorf(codes.FailedPrecondition, "config changed concurrently: have revision %d, got %d", s.revision, req.Revision)
+	}
+
+	s.config = &next
+	s.revision++
+	revision := s.revision
+
+	for ch := range s.watchers {
+		select {
+		case ch <- proto.Clone(&next).(*pb.DetectionConfig):
+		default:
+		}
+	}
+	s.mu.Unlock()
+
+	s.detector.Set(NewNGramDetector(filterProfiles(s.profiles, next.EnabledLanguages)))
+	s.backend.Set(backendForProvider(next.Provider))
+
+	return &pb.UpdateConfigResponse{Revision: revision}, nil
+}
+
+func (s *configServer) WatchConfig(req *pb.WatchConfigRequest, stream pb.ConfigService_WatchConfigServer) error {
+	ch := make(chan *pb.DetectionConfig, 1)
+
+	s.mu.Lock()
+	s.watchers[ch] = struct{}{}
+	current, _ := proto.Clone(s.config).(*pb.DetectionConfig)
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.watchers, ch)
+		s.mu.Unlock()
+	}()
+
+	if err := sendConfig(stream, current); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case cfg := <-ch:
+			if err := sendConfig(stream, cfg); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+func sendConfig(stream pb.ConfigService_WatchConfigServer, cfg *pb.DetectionConfig) error {
+	any, err := anypb.New(cfg)
+	if err != nil {
+		return status.Errorf(codes.Internal, "marshaling config: %v", err)
+	}
+	return stream.Send(&pb.GetConfigResponse{Config: any})
+}
+
+// filterProfiles returns the subset of profiles keyed by enabledLanguages,
+// or all of profiles if enabledLanguages is empty (meaning "every bundled
+// language").
+func filterProfiles(profiles map[string]*Profile, enabledLanguages []string) map[string]*Profile {
+	if len(enabledLanguages) == 0 {
+		return profiles
+	}
+
+	filtered := make(map[string]*Profile, len(enabledLanguages))
+	for _, code := range enabledLanguages {
+		if p, ok := profiles[code]; ok {
+			filtered[code] = p
+		}
+	}
+	return filtered
+}
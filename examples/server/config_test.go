@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/Hovhannesmn/ld_proto/pb"
+)
+
+func newTestConfigServer(t *testing.T) *configServer {
+	t.Helper()
+
+	profiles, err := defaultProfiles()
+	if err != nil {
+		t.Fatalf("defaultProfiles: %v", err)
+	}
+
+	detector := newDetectorHolder(NewNGramDetector(profiles))
+	backend := newBackendHolder(EchoBackend{})
+	return newConfigServer(detector, backend, profiles, defaultDetectionConfig())
+}
+
+func TestConfigServerUpdateConfigRejectsStaleRevision(t *testing.T) {
+	s := newTestConfigServer(t)
+
+	cfg, err := anypb.New(defaultDetectionConfig())
+	if err != nil {
+		t.Fatalf("anypb.New: %v", err)
+	}
+
+	_, err = s.UpdateConfig(context.Background(), &pb.UpdateConfigRequest{Config: cfg, Revision: s.revision - 1})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("UpdateConfig with stale revision: got err %v, want FailedPrecondition", err)
+	}
+	if got := s.currentConfig(); got.Provider != defaultDetectionConfig().Provider {
+		t.Errorf("a rejected update must not change the stored config; got Provider %q", got.Provider)
+	}
+}
+
+func TestConfigServerUpdateConfigHotSwapsDetectorAndBackend(t *testing.T) {
+	s := newTestConfigServer(t)
+
+	next := &pb.DetectionConfig{
+		MinTextLength:       1,
+		ConfidenceThreshold: lowConfidenceThreshold,
+		EnabledLanguages:    []string{"en"},
+		Provider:            "ld_proto_example",
+	}
+	cfg, err := anypb.New(next)
+	if err != nil {
+		t.Fatalf("anypb.New: %v", err)
+	}
+
+	resp, err := s.UpdateConfig(context.Background(), &pb.UpdateConfigRequest{Config: cfg, Revision: s.revision})
+	if err != nil {
+		t.Fatalf("UpdateConfig: %v", err)
+	}
+	if resp.Revision != 2 {
+		t.Errorf("Revision = %d, want 2", resp.Revision)
+	}
+
+	// The detector is now restricted to the "en" profile, so even a
+	// clearly French sentence must come back as "en" -- proof the
+	// UpdateConfig really swapped the live Detector, not just recorded
+	// EnabledLanguages.
+	languageCode, _, _ := s.detector.Detect("Bonjour, ceci est un document en francais.")
+	if languageCode != "en" {
+		t.Errorf("Detect after EnabledLanguages=[en] = %q, want en", languageCode)
+	}
+
+	s.backend.mu.RLock()
+	_, isEcho := s.backend.backend.(EchoBackend)
+	s.backend.mu.RUnlock()
+	if !isEcho {
+		t.Errorf("backend after Provider=%q update = %T, want EchoBackend", next.Provider, s.backend.backend)
+	}
+}
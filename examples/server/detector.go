@@ -0,0 +1,34 @@
+package main
+
+import "github.com/Hovhannesmn/ld_proto/pb"
+
+// Alternative is a candidate language and the detector's confidence in
+// it. It's independent of the gRPC wire format so a Detector
+// implementation never needs to import pb directly.
+type Alternative struct {
+	LanguageCode string
+	Confidence   float32
+}
+
+// Detector identifies the language of a piece of text. Third parties can
+// implement this interface to plug in their own classifier in place of
+// the bundled n-gram model.
+type Detector interface {
+	Detect(text string) (code string, confidence float32, alternatives []Alternative)
+}
+
+// toPBAlternatives converts detector-native alternatives to the wire
+// type used by DetectLanguageResponse.
+func toPBAlternatives(alts []Alternative) []*pb.LanguageAlternative {
+	if len(alts) == 0 {
+		return nil
+	}
+	out := make([]*pb.LanguageAlternative, len(alts))
+	for i, alt := range alts {
+		out[i] = &pb.LanguageAlternative{
+			LanguageCode: alt.LanguageCode,
+			Confidence:   alt.Confidence,
+		}
+	}
+	return out
+}
@@ -0,0 +1,31 @@
+package main
+
+import "sync"
+
+// detectorHolder lets ConfigService hot-swap the active Detector (e.g.
+// when the enabled language set changes) without the DetectLanguage and
+// TranslateText handlers needing to know config ever changed; it
+// implements Detector itself by delegating to whichever detector is
+// currently set.
+type detectorHolder struct {
+	mu       sync.RWMutex
+	detector Detector
+}
+
+func newDetectorHolder(detector Detector) *detectorHolder {
+	return &detectorHolder{detector: detector}
+}
+
+func (h *detectorHolder) Detect(text string) (string, float32, []Alternative) {
+	h.mu.RLock()
+	detector := h.detector
+	h.mu.RUnlock()
+	return detector.Detect(text)
+}
+
+// Set swaps in a new Detector for subsequent calls.
+func (h *detectorHolder) Set(detector Detector) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.detector = detector
+}
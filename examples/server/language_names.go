@@ -0,0 +1,35 @@
+package main
+
+// languageNames maps a detected language code to its English display
+// name, for interpolating into localized messages (e.g. "Detected
+// Spanish with low confidence..."). Codes outside this set fall back to
+// the code itself.
+var languageNames = map[string]string{
+	"en": "English",
+	"es": "Spanish",
+	"fr": "French",
+	"de": "German",
+	"it": "Italian",
+	"pt": "Portuguese",
+	"nl": "Dutch",
+	"sv": "Swedish",
+	"da": "Danish",
+	"nb": "Norwegian",
+	"pl": "Polish",
+	"ro": "Romanian",
+	"ru": "Russian",
+	"tr": "Turkish",
+	"cs": "Czech",
+	"fi": "Finnish",
+	"hu": "Hungarian",
+	"el": "Greek",
+	"id": "Indonesian",
+	"vi": "Vietnamese",
+}
+
+func languageName(code string) string {
+	if name, ok := languageNames[code]; ok {
+		return name
+	}
+	return code
+}
@@ -2,114 +2,229 @@ package main
 
 import (
 	"context"
+	"io"
 	"log"
 	"net"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
+	"github.com/Hovhannesmn/ld_proto/pb"
+	"github.com/Hovhannesmn/ld_proto_examples/utils/i18n"
 	"google.golang.org/grpc"
-	"github.com/hovman/ld_proto/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-// Simple language detection based on common words
-func detectLanguage(text string) (string, float32, []*pb.LanguageAlternative) {
-	text = strings.ToLower(text)
-	
-	// Simple word-based detection
-	languageWords := map[string][]string{
-		"en": {"the", "and", "is", "in", "to", "of", "a", "that", "it", "with"},
-		"es": {"el", "la", "de", "que", "y", "a", "en", "un", "es", "se"},
-		"fr": {"le", "de", "et", "à", "un", "il", "être", "et", "en", "avoir"},
-		"de": {"der", "die", "und", "in", "den", "von", "zu", "das", "mit", "sich"},
-		"it": {"il", "di", "che", "e", "la", "per", "un", "in", "con", "da"},
-	}
-	
-	scores := make(map[string]int)
-	totalWords := 0
-	
-	words := strings.Fields(text)
-	for _, word := range words {
-		totalWords++
-		for lang, langWords := range languageWords {
-			for _, langWord := range langWords {
-				if word == langWord {
-					scores[lang]++
-				}
-			}
-		}
-	}
-	
-	if totalWords == 0 {
-		return "unknown", 0.0, nil
-	}
-	
-	// Find best match
-	bestLang := "en" // default
-	bestScore := 0
-	for lang, score := range scores {
-		if score > bestScore {
-			bestScore = score
-			bestLang = lang
-		}
-	}
-	
-	confidence := float32(bestScore) / float32(totalWords)
-	if confidence > 1.0 {
-		confidence = 1.0
-	}
-	
-	// Create alternatives
-	var alternatives []*pb.LanguageAlternative
-	for lang, score := range scores {
-		if lang != bestLang && score > 0 {
-			altConfidence := float32(score) / float32(totalWords)
-			if altConfidence > 1.0 {
-				altConfidence = 1.0
-			}
-			alternatives = append(alternatives, &pb.LanguageAlternative{
-				LanguageCode: lang,
-				Confidence:   altConfidence,
-			})
-		}
+// maxStreamWorkers bounds how many documents a single DetectLanguageStream
+// call will process concurrently, so one greedy client can't starve the
+// others sharing the server's goroutine pool.
+const maxStreamWorkers = 8
+
+// lowConfidenceThreshold is the ConfidenceThreshold the server starts with
+// before any ldctl config update changes it.
+const lowConfidenceThreshold = 0.3
+
+// responseLanguage picks the language a response's LocalizedMessage
+// should be written in: the request's explicit response_language field if
+// set, otherwise whatever i18n.UnaryServerInterceptor or
+// i18n.StreamServerInterceptor attached to ctx.
+func responseLanguage(ctx context.Context, requested string) string {
+	if requested != "" {
+		return requested
 	}
-	
-	return bestLang, confidence, alternatives
+	return i18n.LanguageFromContext(ctx)
+}
+
+// configProvider exposes the config a running configServer currently
+// holds, so the detection handlers can read MinTextLength and
+// ConfidenceThreshold as last set via ConfigService instead of values
+// fixed at startup.
+type configProvider interface {
+	currentConfig() *pb.DetectionConfig
 }
 
 type server struct {
 	pb.UnimplementedLanguageDetectionServiceServer
+
+	detector Detector
+	config   configProvider
+}
+
+// newServer wires a server to the given Detector and configProvider, so
+// the n-gram model can be swapped out (in tests, or for a third party's
+// own classifier) and the detection thresholds retuned at runtime without
+// touching the RPC handlers.
+func newServer(detector Detector, config configProvider) *server {
+	return &server{detector: detector, config: config}
+}
+
+// validationError checks text against the same rules DetectLanguage and
+// DetectLanguageStream must both enforce -- non-empty after trimming, and
+// at least cfg.MinTextLength long -- returning the localized message for
+// whichever rule text fails, or "" if text is valid.
+func validationError(lang, text string, cfg *pb.DetectionConfig) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return i18n.T(lang, "error.empty_text")
+	}
+	if int32(utf8.RuneCountInString(trimmed)) < cfg.MinTextLength {
+		return i18n.T(lang, "error.text_too_short", cfg.MinTextLength)
+	}
+	return ""
 }
 
 func (s *server) DetectLanguage(ctx context.Context, req *pb.DetectLanguageRequest) (*pb.DetectLanguageResponse, error) {
+	lang := responseLanguage(ctx, req.ResponseLanguage)
+	cfg := s.config.currentConfig()
+
+	if msg := validationError(lang, req.Text, cfg); msg != "" {
+		return nil, status.Error(codes.InvalidArgument, msg)
+	}
+
 	start := time.Now()
-	
-	// Perform language detection
-	languageCode, confidence, alternatives := detectLanguage(req.Text)
-	
+
+	languageCode, confidence, alternatives := s.detector.Detect(req.Text)
+
 	processingTime := time.Since(start)
-	
+
+	var localizedMessage string
+	if confidence < cfg.ConfidenceThreshold {
+		localizedMessage = i18n.T(lang, "detection.low_confidence", languageName(languageCode))
+	}
+
 	return &pb.DetectLanguageResponse{
-		LanguageCode: languageCode,
-		Confidence:   confidence,
-		Alternatives: alternatives,
-		DocumentId:   req.DocumentId,
+		LanguageCode:     languageCode,
+		Confidence:       confidence,
+		Alternatives:     toPBAlternatives(alternatives),
+		DocumentId:       req.DocumentId,
+		LocalizedMessage: localizedMessage,
 		Metadata: &pb.ProcessingMetadata{
 			ProcessingTimeMs: processingTime.Milliseconds(),
 			ServiceVersion:   "1.0.0",
-			ModelVersion:     "simple-word-based-v1.0",
+			ModelVersion:     "ngram-cavnar-trenkle-v1.0",
 			Provider:         "ld_proto_example",
 		},
 	}, nil
 }
 
+// DetectLanguageStream lets a client push documents as they become
+// available and receive responses as soon as each one finishes, instead of
+// paying one round-trip per document. Documents are fanned out across a
+// bounded worker pool, so responses can arrive out of order relative to
+// the requests that produced them; callers match them back up by
+// DocumentId.
+func (s *server) DetectLanguageStream(stream pb.LanguageDetectionService_DetectLanguageStreamServer) error {
+	ctx := stream.Context()
+
+	sem := make(chan struct{}, maxStreamWorkers)
+	var wg sync.WaitGroup
+
+	// Send is not safe for concurrent use, so every worker funnels its
+	// response through sendMu.
+	var sendMu sync.Mutex
+	var sendErr error
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			wg.Wait()
+			return err
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		}
+
+		wg.Add(1)
+		go func(req *pb.DetectLanguageRequest) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			lang := responseLanguage(ctx, req.ResponseLanguage)
+			cfg := s.config.currentConfig()
+
+			var resp *pb.DetectLanguageResponse
+			if msg := validationError(lang, req.Text, cfg); msg != "" {
+				// A stream can't fail one document's RPC status without
+				// failing every other document on the same connection,
+				// so an invalid document gets its error back as a
+				// response instead: LanguageCode is left unset (unlike
+				// the detector's own "unknown", which means "I looked
+				// and couldn't tell") and LocalizedMessage explains why.
+				resp = &pb.DetectLanguageResponse{
+					DocumentId:       req.DocumentId,
+					LocalizedMessage: msg,
+				}
+			} else {
+				start := time.Now()
+				languageCode, confidence, alternatives := s.detector.Detect(req.Text)
+
+				var localizedMessage string
+				if confidence < cfg.ConfidenceThreshold {
+					localizedMessage = i18n.T(lang, "detection.low_confidence", languageName(languageCode))
+				}
+
+				resp = &pb.DetectLanguageResponse{
+					LanguageCode:     languageCode,
+					Confidence:       confidence,
+					Alternatives:     toPBAlternatives(alternatives),
+					DocumentId:       req.DocumentId,
+					LocalizedMessage: localizedMessage,
+					Metadata: &pb.ProcessingMetadata{
+						ProcessingTimeMs: time.Since(start).Milliseconds(),
+						ServiceVersion:   "1.0.0",
+						ModelVersion:     "ngram-cavnar-trenkle-v1.0",
+						Provider:         "ld_proto_example",
+					},
+				}
+			}
+
+			sendMu.Lock()
+			defer sendMu.Unlock()
+			if sendErr == nil {
+				if err := stream.Send(resp); err != nil {
+					sendErr = err
+				}
+			}
+		}(req)
+	}
+
+	wg.Wait()
+	return sendErr
+}
+
 func main() {
+	profiles, err := defaultProfiles()
+	if err != nil {
+		log.Fatalf("Failed to load language profiles: %v", err)
+	}
+
 	lis, err := net.Listen("tcp", ":50051")
 	if err != nil {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
-	s := grpc.NewServer()
-	pb.RegisterLanguageDetectionServiceServer(s, &server{})
+	detector := newDetectorHolder(NewNGramDetector(profiles))
+	backend := newBackendHolder(EchoBackend{})
+	config := newConfigServer(detector, backend, profiles, defaultDetectionConfig())
+
+	s := grpc.NewServer(
+		grpc.UnaryInterceptor(i18n.UnaryServerInterceptor()),
+		grpc.StreamInterceptor(i18n.StreamServerInterceptor()),
+	)
+	pb.RegisterLanguageDetectionServiceServer(s, newServer(detector, config))
+	// backend starts out as EchoBackend; an ldctl config update with a
+	// recognized Provider hot-swaps it via backendForProvider.
+	pb.RegisterTranslationServiceServer(s, newTranslationServer(backend, detector))
+	pb.RegisterConfigServiceServer(s, config)
 
 	log.Println("Language Detection Server starting on :50051")
 	log.Println("Use the client example to test the service")
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strings"
+)
+
+// profileSize is how many of the most frequent n-grams are kept per
+// language profile, following Cavnar & Trenkle's "N-Gram-Based Text
+// Categorization" (1994).
+const profileSize = 300
+
+// minNGramLen and maxNGramLen bound the n-gram lengths extracted from
+// each padded word, e.g. "_hello_" yields n-grams for n=1..5.
+const (
+	minNGramLen = 1
+	maxNGramLen = 5
+)
+
+// Profile is a language's character n-grams ordered from most to least
+// frequent. Classification compares rank in this list, not raw
+// frequency, across languages.
+type Profile struct {
+	Language string
+	NGrams   []string
+
+	rank map[string]int
+}
+
+// TrainProfile builds a Profile for language from a text corpus, so users
+// can add support for a language the bundled profiles don't cover.
+func TrainProfile(language string, corpus io.Reader) (*Profile, error) {
+	freq := make(map[string]int)
+
+	scanner := bufio.NewScanner(corpus)
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		tallyWordNGrams(freq, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return newProfile(language, freq), nil
+}
+
+// newProfile orders an n-gram frequency table from most to least
+// frequent, ties broken alphabetically so training is deterministic, and
+// keeps only the top profileSize entries.
+func newProfile(language string, freq map[string]int) *Profile {
+	ngrams := make([]string, 0, len(freq))
+	for ng := range freq {
+		ngrams = append(ngrams, ng)
+	}
+	sort.Slice(ngrams, func(i, j int) bool {
+		if freq[ngrams[i]] != freq[ngrams[j]] {
+			return freq[ngrams[i]] > freq[ngrams[j]]
+		}
+		return ngrams[i] < ngrams[j]
+	})
+	if len(ngrams) > profileSize {
+		ngrams = ngrams[:profileSize]
+	}
+
+	rank := make(map[string]int, len(ngrams))
+	for i, ng := range ngrams {
+		rank[ng] = i
+	}
+
+	return &Profile{Language: language, NGrams: ngrams, rank: rank}
+}
+
+// tallyWordNGrams pads word with underscores, so leading and trailing
+// n-grams carry word-boundary information, and tallies every n-gram of
+// length minNGramLen..maxNGramLen found in it.
+func tallyWordNGrams(freq map[string]int, word string) {
+	runes := []rune("_" + strings.ToLower(word) + "_")
+
+	for n := minNGramLen; n <= maxNGramLen; n++ {
+		if len(runes) < n {
+			continue
+		}
+		for i := 0; i+n <= len(runes); i++ {
+			freq[string(runes[i:i+n])]++
+		}
+	}
+}
+
+// distance computes the Cavnar-Trenkle "out-of-place" distance between an
+// input profile and p: for every n-gram in input, add the gap between its
+// rank in input and its rank in p, or a flat profileSize penalty if p
+// never saw that n-gram at all.
+func (p *Profile) distance(input *Profile) int {
+	total := 0
+	for i, ng := range input.NGrams {
+		if langRank, ok := p.rank[ng]; ok {
+			if gap := i - langRank; gap >= 0 {
+				total += gap
+			} else {
+				total += -gap
+			}
+		} else {
+			total += profileSize
+		}
+	}
+	return total
+}
+
+// NGramDetector is a Cavnar-Trenkle style character n-gram classifier: it
+// builds the same kind of profile from the input text and picks whichever
+// language profile is "closest" to it by out-of-place distance.
+type NGramDetector struct {
+	profiles map[string]*Profile
+}
+
+// NewNGramDetector returns a Detector backed by the given language
+// profiles, keyed by language code.
+func NewNGramDetector(profiles map[string]*Profile) *NGramDetector {
+	return &NGramDetector{profiles: profiles}
+}
+
+func (d *NGramDetector) Detect(text string) (string, float32, []Alternative) {
+	input, err := TrainProfile("input", strings.NewReader(text))
+	if err != nil || len(input.NGrams) == 0 || len(d.profiles) == 0 {
+		return "unknown", 0, nil
+	}
+
+	type candidate struct {
+		language string
+		distance int
+	}
+	candidates := make([]candidate, 0, len(d.profiles))
+	for lang, profile := range d.profiles {
+		candidates = append(candidates, candidate{lang, profile.distance(input)})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].language < candidates[j].language
+	})
+
+	best := candidates[0]
+
+	confidence := float32(1)
+	if len(candidates) > 1 && candidates[1].distance > 0 {
+		confidence = 1 - float32(best.distance)/float32(candidates[1].distance)
+	}
+
+	alternatives := make([]Alternative, 0, len(candidates)-1)
+	for _, c := range candidates[1:] {
+		altConfidence := float32(0)
+		if c.distance > 0 {
+			altConfidence = 1 - float32(best.distance)/float32(c.distance)
+		}
+		alternatives = append(alternatives, Alternative{LanguageCode: c.language, Confidence: altConfidence})
+	}
+
+	return best.language, confidence, alternatives
+}
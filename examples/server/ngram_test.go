@@ -0,0 +1,55 @@
+package main
+
+import "testing"
+
+func TestNGramDetectorDetect(t *testing.T) {
+	profiles, err := defaultProfiles()
+	if err != nil {
+		t.Fatalf("defaultProfiles: %v", err)
+	}
+	detector := NewNGramDetector(profiles)
+
+	// These sentences are UDHR Article 13 ("freedom of movement and
+	// residence"), deliberately not part of the Article 1-6 text the
+	// bundled profiles are trained on. A detector that only memorized
+	// the training sentences verbatim would fail these; one that
+	// actually learned the language's n-gram distribution won't.
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "english",
+			text: "Everyone has the right to freedom of movement and residence within the borders of each state.",
+			want: "en",
+		},
+		{
+			name: "spanish",
+			text: "Toda persona tiene derecho a circular libremente y a elegir su residencia en el territorio de un Estado.",
+			want: "es",
+		},
+		{
+			name: "french",
+			text: "Toute personne a le droit de circuler librement et de choisir sa residence a l'interieur d'un Etat.",
+			want: "fr",
+		},
+		{
+			name: "german",
+			text: "Jeder hat das Recht, sich innerhalb eines Staates frei zu bewegen und seinen Aufenthaltsort frei zu wahlen.",
+			want: "de",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, confidence, _ := detector.Detect(tt.text)
+			if got != tt.want {
+				t.Errorf("Detect(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+			if confidence <= 0 {
+				t.Errorf("Detect(%q) confidence = %v, want > 0", tt.text, confidence)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"strings"
+)
+
+//go:embed profiles/*.txt
+var profileCorpora embed.FS
+
+// defaultProfiles trains a Profile per bundled corpus under profiles/.
+// Each file is a short multi-paragraph text sample for its language,
+// named <code>.txt. The samples are small enough that the last entries
+// of each top-300 profile are n-grams tied at a frequency of 2 or 3,
+// ordered alphabetically. Use TrainProfile directly to add a language
+// these don't cover.
+func defaultProfiles() (map[string]*Profile, error) {
+	entries, err := profileCorpora.ReadDir("profiles")
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := make(map[string]*Profile, len(entries))
+	for _, entry := range entries {
+		code, ok := strings.CutSuffix(entry.Name(), ".txt")
+		if !ok {
+			continue
+		}
+
+		data, err := profileCorpora.ReadFile("profiles/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("reading profile %s: %w", entry.Name(), err)
+		}
+
+		profile, err := TrainProfile(code, strings.NewReader(string(data)))
+		if err != nil {
+			return nil, fmt.Errorf("training profile %s: %w", entry.Name(), err)
+		}
+		profiles[code] = profile
+	}
+
+	return profiles, nil
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+
+	"github.com/Hovhannesmn/ld_proto/pb"
+)
+
+// TranslationBackend performs the actual text translation for
+// translationServer. Swapping the backend lets the example point at a
+// different provider, or at the no-op EchoBackend in tests, without
+// touching the RPC handlers.
+type TranslationBackend interface {
+	Translate(ctx context.Context, text, sourceLang, targetLang, mimeType string) (string, error)
+}
+
+type translationServer struct {
+	pb.UnimplementedTranslationServiceServer
+
+	backend  TranslationBackend
+	detector Detector
+}
+
+// newTranslationServer wires a translationServer to the given backend and
+// detector. detector is used to auto-detect the source language whenever
+// a caller leaves SourceLanguageCode empty.
+func newTranslationServer(backend TranslationBackend, detector Detector) *translationServer {
+	return &translationServer{backend: backend, detector: detector}
+}
+
+func (s *translationServer) TranslateText(ctx context.Context, req *pb.TranslateTextRequest) (*pb.TranslateTextResponse, error) {
+	translations, err := s.translateAll(ctx, []string{req.Content}, req.SourceLanguageCode, req.TargetLanguageCode, req.MimeType)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.TranslateTextResponse{
+		TranslatedText:       translations[0].text,
+		DetectedLanguageCode: translations[0].detectedLanguageCode,
+	}, nil
+}
+
+func (s *translationServer) BatchTranslateText(ctx context.Context, req *pb.BatchTranslateTextRequest) (*pb.BatchTranslateTextResponse, error) {
+	translations, err := s.translateAll(ctx, req.Contents, req.SourceLanguageCode, req.TargetLanguageCode, req.MimeType)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.BatchTranslateTextResponse{
+		Translations: make([]*pb.TranslateTextResponse, len(translations)),
+	}
+	for i, t := range translations {
+		resp.Translations[i] = &pb.TranslateTextResponse{
+			TranslatedText:       t.text,
+			DetectedLanguageCode: t.detectedLanguageCode,
+		}
+	}
+	return resp, nil
+}
+
+type translationResult struct {
+	text                 string
+	detectedLanguageCode string
+}
+
+// translateAll translates each of contents, auto-detecting the source
+// language per-content whenever sourceLang is empty so a batch can mix
+// documents in different languages in one call.
+func (s *translationServer) translateAll(ctx context.Context, contents []string, sourceLang, targetLang, mimeType string) ([]translationResult, error) {
+	results := make([]translationResult, len(contents))
+	for i, content := range contents {
+		lang := sourceLang
+		if lang == "" {
+			lang, _, _ = s.detector.Detect(content)
+		}
+
+		translated, err := s.backend.Translate(ctx, content, lang, targetLang, mimeType)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = translationResult{text: translated, detectedLanguageCode: lang}
+	}
+	return results, nil
+}
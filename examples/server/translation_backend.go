@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// EchoBackend is a no-op TranslationBackend that returns the input text
+// unchanged. It's useful for tests and local runs that only care about
+// the RPC plumbing, not translation quality.
+type EchoBackend struct{}
+
+func (EchoBackend) Translate(ctx context.Context, text, sourceLang, targetLang, mimeType string) (string, error) {
+	return text, nil
+}
+
+// LibreTranslateBackend calls a LibreTranslate instance
+// (https://github.com/LibreTranslate/LibreTranslate) over its HTTP API.
+type LibreTranslateBackend struct {
+	BaseURL string
+	APIKey  string
+	Client  *http.Client
+}
+
+// NewLibreTranslateBackend returns a backend pointed at the LibreTranslate
+// instance at baseURL, e.g. "https://libretranslate.com".
+func NewLibreTranslateBackend(baseURL, apiKey string) *LibreTranslateBackend {
+	return &LibreTranslateBackend{BaseURL: baseURL, APIKey: apiKey}
+}
+
+type libreTranslateRequest struct {
+	Q      string `json:"q"`
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Format string `json:"format"`
+	APIKey string `json:"api_key,omitempty"`
+}
+
+type libreTranslateResponse struct {
+	TranslatedText string `json:"translatedText"`
+}
+
+func (b *LibreTranslateBackend) Translate(ctx context.Context, text, sourceLang, targetLang, mimeType string) (string, error) {
+	format := "text"
+	if mimeType == "text/html" {
+		format = "html"
+	}
+
+	body, err := json.Marshal(libreTranslateRequest{
+		Q:      text,
+		Source: sourceLang,
+		Target: targetLang,
+		Format: format,
+		APIKey: b.APIKey,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.BaseURL+"/translate", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := b.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("libretranslate: unexpected status %s", resp.Status)
+	}
+
+	var out libreTranslateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", err
+	}
+	return out.TranslatedText, nil
+}
+
+// backendHolder lets configServer hot-swap the TranslationBackend a running
+// translationServer uses, mirroring detectorHolder's approach to swapping
+// the active Detector on a config update.
+type backendHolder struct {
+	mu      sync.RWMutex
+	backend TranslationBackend
+}
+
+func newBackendHolder(backend TranslationBackend) *backendHolder {
+	return &backendHolder{backend: backend}
+}
+
+func (h *backendHolder) Translate(ctx context.Context, text, sourceLang, targetLang, mimeType string) (string, error) {
+	h.mu.RLock()
+	backend := h.backend
+	h.mu.RUnlock()
+	return backend.Translate(ctx, text, sourceLang, targetLang, mimeType)
+}
+
+func (h *backendHolder) Set(backend TranslationBackend) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.backend = backend
+}
+
+// backendForProvider maps a DetectionConfig.Provider value to the
+// TranslationBackend it names. Unrecognized or empty providers fall back to
+// EchoBackend so an invalid ldctl config update degrades to a no-op
+// translation rather than failing closed.
+func backendForProvider(provider string) TranslationBackend {
+	switch provider {
+	case "libretranslate":
+		return NewLibreTranslateBackend(os.Getenv("LIBRETRANSLATE_URL"), os.Getenv("LIBRETRANSLATE_API_KEY"))
+	case "ld_proto_example", "":
+		return EchoBackend{}
+	default:
+		return EchoBackend{}
+	}
+}
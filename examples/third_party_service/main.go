@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"io"
 	"log"
 	"time"
 
@@ -12,13 +13,15 @@ import (
 
 // ThirdPartyService demonstrates how to integrate ld_proto in your own service
 type ThirdPartyService struct {
-	languageClient pb.LanguageDetectionServiceClient
+	languageClient    pb.LanguageDetectionServiceClient
+	translationClient pb.TranslationServiceClient
 }
 
 // NewThirdPartyService creates a new service instance
 func NewThirdPartyService(grpcConn *grpc.ClientConn) *ThirdPartyService {
 	return &ThirdPartyService{
-		languageClient: pb.NewLanguageDetectionServiceClient(grpcConn),
+		languageClient:    pb.NewLanguageDetectionServiceClient(grpcConn),
+		translationClient: pb.NewTranslationServiceClient(grpcConn),
 	}
 }
 
@@ -42,9 +45,15 @@ func (s *ThirdPartyService) ProcessDocument(ctx context.Context, content, docID
 		return nil, err
 	}
 
-	// Process the response
+	return responseToDocumentInfo(resp, content), nil
+}
+
+// responseToDocumentInfo converts a detection response into the service's
+// own DocumentInfo shape. It's shared by ProcessDocument and
+// BatchProcessDocuments so the two code paths can't drift apart.
+func responseToDocumentInfo(resp *pb.DetectLanguageResponse, content string) *DocumentInfo {
 	docInfo := &DocumentInfo{
-		ID:           docID,
+		ID:           resp.DocumentId,
 		Content:      content,
 		Language:     resp.LanguageCode,
 		Confidence:   resp.Confidence,
@@ -52,7 +61,6 @@ func (s *ThirdPartyService) ProcessDocument(ctx context.Context, content, docID
 		Alternatives: make([]LanguageAlternative, len(resp.Alternatives)),
 	}
 
-	// Convert alternatives
 	for i, alt := range resp.Alternatives {
 		docInfo.Alternatives[i] = LanguageAlternative{
 			Language:   alt.LanguageCode,
@@ -60,7 +68,6 @@ func (s *ThirdPartyService) ProcessDocument(ctx context.Context, content, docID
 		}
 	}
 
-	// Add processing metadata if available
 	if resp.Metadata != nil {
 		docInfo.ProcessingTime = time.Duration(resp.Metadata.ProcessingTimeMs) * time.Millisecond
 		docInfo.ServiceVersion = resp.Metadata.ServiceVersion
@@ -68,7 +75,7 @@ func (s *ThirdPartyService) ProcessDocument(ctx context.Context, content, docID
 		docInfo.Provider = resp.Metadata.Provider
 	}
 
-	return docInfo, nil
+	return docInfo
 }
 
 // DocumentInfo represents a processed document
@@ -91,19 +98,61 @@ type LanguageAlternative struct {
 	Confidence float32
 }
 
-// BatchProcessDocuments processes multiple documents
+// BatchProcessDocuments pushes documents onto a DetectLanguageStream as
+// they arrive and collects the responses as they come back, which may be
+// in a different order than they were sent. This lets a caller push an
+// entire corpus without paying one round-trip per document, the way the
+// unary loop used to. The stream is closed and the context cancelled as
+// soon as the caller's ctx is done or the server returns an error.
 func (s *ThirdPartyService) BatchProcessDocuments(ctx context.Context, documents []Document) ([]*DocumentInfo, error) {
-	results := make([]*DocumentInfo, 0, len(documents))
-	
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := s.languageClient.DetectLanguageStream(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	contentByID := make(map[string]string, len(documents))
 	for _, doc := range documents {
-		docInfo, err := s.ProcessDocument(ctx, doc.Content, doc.ID)
+		contentByID[doc.ID] = doc.Content
+	}
+
+	sendErrCh := make(chan error, 1)
+	go func() {
+		for _, doc := range documents {
+			req := &pb.DetectLanguageRequest{
+				Text:       doc.Content,
+				DocumentId: doc.ID,
+				Metadata: map[string]string{
+					"service": "third_party_service",
+					"version": "1.0.0",
+				},
+			}
+			if err := stream.Send(req); err != nil {
+				sendErrCh <- err
+				return
+			}
+		}
+		sendErrCh <- stream.CloseSend()
+	}()
+
+	results := make([]*DocumentInfo, 0, len(documents))
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
 		if err != nil {
-			log.Printf("Failed to process document %s: %v", doc.ID, err)
-			continue
+			return results, err
 		}
-		results = append(results, docInfo)
+		results = append(results, responseToDocumentInfo(resp, contentByID[resp.DocumentId]))
 	}
-	
+
+	if err := <-sendErrCh; err != nil {
+		return results, err
+	}
+
 	return results, nil
 }
 
@@ -113,6 +162,40 @@ type Document struct {
 	Content string
 }
 
+// TranslatedDocumentInfo is a DocumentInfo plus the result of translating
+// its content into TargetLanguage.
+type TranslatedDocumentInfo struct {
+	*DocumentInfo
+	TranslatedText string
+	TargetLanguage string
+}
+
+// ProcessAndTranslateDocument detects the language of content and then
+// translates it into targetLang, chaining the two RPCs so callers get
+// both the language metadata and the translated text back in one call.
+func (s *ThirdPartyService) ProcessAndTranslateDocument(ctx context.Context, content, docID, targetLang string) (*TranslatedDocumentInfo, error) {
+	docInfo, err := s.ProcessDocument(ctx, content, docID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.translationClient.TranslateText(ctx, &pb.TranslateTextRequest{
+		Content:            content,
+		SourceLanguageCode: docInfo.Language,
+		TargetLanguageCode: targetLang,
+		MimeType:           "text/plain",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &TranslatedDocumentInfo{
+		DocumentInfo:   docInfo,
+		TranslatedText: resp.TranslatedText,
+		TargetLanguage: targetLang,
+	}, nil
+}
+
 func main() {
 	// 1. Connect to the language detection service
 	conn, err := grpc.Dial("localhost:50051", grpc.WithTransportCredentials(insecure.NewCredentials()))
@@ -166,4 +249,12 @@ func main() {
 	for _, result := range batchResults {
 		log.Printf("  %s: %s (%.2f)", result.ID, result.Language, result.Confidence)
 	}
+
+	// 5. Detect and translate a document in one call
+	translated, err := service.ProcessAndTranslateDocument(ctx, "Bonjour, ceci est un document en français.", "doc-006", "en")
+	if err != nil {
+		log.Printf("Process-and-translate failed: %v", err)
+	} else {
+		log.Printf("\nTranslated %s from %s to %s: %s", translated.ID, translated.Language, translated.TargetLanguage, translated.TranslatedText)
+	}
 }
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	"github.com/Hovhannesmn/ld_proto/pb"
+	"github.com/Hovhannesmn/ld_proto_examples/ldtest"
+)
+
+// echoTranslationServer is a minimal pb.TranslationServiceServer stub:
+// it reports the translated text as the content it was asked to
+// translate, so tests can assert ProcessAndTranslateDocument stitched
+// the detection and translation responses together correctly without
+// needing a real translation provider.
+type echoTranslationServer struct {
+	pb.UnimplementedTranslationServiceServer
+}
+
+func (echoTranslationServer) TranslateText(ctx context.Context, req *pb.TranslateTextRequest) (*pb.TranslateTextResponse, error) {
+	return &pb.TranslateTextResponse{
+		TranslatedText:       req.Content,
+		DetectedLanguageCode: req.SourceLanguageCode,
+	}, nil
+}
+
+func TestBatchProcessDocuments(t *testing.T) {
+	fake := ldtest.NewFake()
+	if err := fake.ExpectRequest("espanol", &pb.DetectLanguageResponse{DocumentId: "doc-es", LanguageCode: "es", Confidence: 0.9}); err != nil {
+		t.Fatalf("ExpectRequest: %v", err)
+	}
+	if err := fake.ExpectRequest("francais", &pb.DetectLanguageResponse{DocumentId: "doc-fr", LanguageCode: "fr", Confidence: 0.9}); err != nil {
+		t.Fatalf("ExpectRequest: %v", err)
+	}
+
+	conn := ldtest.NewInProcessConn(t, func(srv *grpc.Server) {
+		pb.RegisterLanguageDetectionServiceServer(srv, fake)
+	})
+	service := NewThirdPartyService(conn)
+
+	documents := []Document{
+		{ID: "doc-es", Content: "Hola, espanol."},
+		{ID: "doc-fr", Content: "Bonjour, francais."},
+	}
+
+	results, err := service.BatchProcessDocuments(context.Background(), documents)
+	if err != nil {
+		t.Fatalf("BatchProcessDocuments: %v", err)
+	}
+	if len(results) != len(documents) {
+		t.Fatalf("got %d results, want %d", len(results), len(documents))
+	}
+
+	byID := make(map[string]*DocumentInfo, len(results))
+	for _, r := range results {
+		byID[r.ID] = r
+	}
+
+	if got := byID["doc-es"]; got == nil || got.Language != "es" {
+		t.Errorf("doc-es: got %+v, want language es", got)
+	}
+	if got := byID["doc-fr"]; got == nil || got.Language != "fr" {
+		t.Errorf("doc-fr: got %+v, want language fr", got)
+	}
+}
+
+func TestProcessAndTranslateDocument(t *testing.T) {
+	fake := ldtest.NewFake()
+	if err := fake.ExpectRequest(".*", &pb.DetectLanguageResponse{DocumentId: "doc-001", LanguageCode: "fr", Confidence: 0.95}); err != nil {
+		t.Fatalf("ExpectRequest: %v", err)
+	}
+
+	conn := ldtest.NewInProcessConn(t, func(srv *grpc.Server) {
+		pb.RegisterLanguageDetectionServiceServer(srv, fake)
+		pb.RegisterTranslationServiceServer(srv, echoTranslationServer{})
+	})
+	service := NewThirdPartyService(conn)
+
+	content := "Bonjour, ceci est un document en francais."
+	translated, err := service.ProcessAndTranslateDocument(context.Background(), content, "doc-001", "en")
+	if err != nil {
+		t.Fatalf("ProcessAndTranslateDocument: %v", err)
+	}
+
+	if translated.Language != "fr" {
+		t.Errorf("Language = %q, want fr", translated.Language)
+	}
+	if translated.TargetLanguage != "en" {
+		t.Errorf("TargetLanguage = %q, want en", translated.TargetLanguage)
+	}
+	if translated.TranslatedText != content {
+		t.Errorf("TranslatedText = %q, want %q", translated.TranslatedText, content)
+	}
+}
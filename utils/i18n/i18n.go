@@ -0,0 +1,141 @@
+// Package i18n loads per-language JSON translation bundles and formats
+// messages from them, with fallback to English. It's kept independent of
+// any one RPC service so other consumers of pb can reuse it for their own
+// localized responses.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+//go:embed locales/*.json
+var bundles embed.FS
+
+// fallbackLanguage is used whenever a requested language has no bundle,
+// or the bundle is missing a key.
+const fallbackLanguage = "en"
+
+var loaded = map[string]map[string]string{}
+
+func init() {
+	entries, err := bundles.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("i18n: reading locales: %v", err))
+	}
+
+	for _, entry := range entries {
+		lang, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok {
+			continue
+		}
+
+		data, err := bundles.ReadFile("locales/" + entry.Name())
+		if err != nil {
+			panic(fmt.Sprintf("i18n: reading %s: %v", entry.Name(), err))
+		}
+
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: parsing %s: %v", entry.Name(), err))
+		}
+		loaded[lang] = messages
+	}
+}
+
+// T looks up key in lang's translation bundle and formats it with args,
+// falling back to the English bundle and then to the raw key if lang or
+// the key aren't available.
+func T(lang, key string, args ...any) string {
+	if msg, ok := lookup(lang, key); ok {
+		return fmt.Sprintf(msg, args...)
+	}
+	if msg, ok := lookup(fallbackLanguage, key); ok {
+		return fmt.Sprintf(msg, args...)
+	}
+	return key
+}
+
+func lookup(lang, key string) (string, bool) {
+	messages, ok := loaded[lang]
+	if !ok {
+		return "", false
+	}
+	msg, ok := messages[key]
+	return msg, ok
+}
+
+type contextKey struct{}
+
+// WithLanguage attaches lang to ctx so downstream calls (logging, nested
+// RPCs, ...) can localize without re-threading a BCP-47 code through
+// every function signature.
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, contextKey{}, lang)
+}
+
+// LanguageFromContext returns the language attached by WithLanguage, or
+// fallbackLanguage if none was attached.
+func LanguageFromContext(ctx context.Context) string {
+	if lang, ok := ctx.Value(contextKey{}).(string); ok && lang != "" {
+		return lang
+	}
+	return fallbackLanguage
+}
+
+// responseLanguageMetadataKey is the gRPC metadata key
+// UnaryServerInterceptor reads the caller's preferred response language
+// from.
+const responseLanguageMetadataKey = "response-language"
+
+// UnaryServerInterceptor attaches the caller's preferred response
+// language (from the "response-language" metadata key) to the request
+// context via WithLanguage, so handlers can call
+// T(LanguageFromContext(ctx), key, args...) without every RPC needing its
+// own response-language field.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if langs := md.Get(responseLanguageMetadataKey); len(langs) > 0 {
+				ctx = WithLanguage(ctx, langs[0])
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor's streaming-RPC
+// counterpart: it attaches the caller's preferred response language to
+// the stream's context the same way UnaryServerInterceptor does for
+// unary calls, so handlers can call
+// LanguageFromContext(stream.Context()) regardless of RPC style.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := stream.Context()
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if langs := md.Get(responseLanguageMetadataKey); len(langs) > 0 {
+				ctx = WithLanguage(ctx, langs[0])
+			}
+		}
+		return handler(srv, &languageServerStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// languageServerStream wraps a grpc.ServerStream to override Context(),
+// the same trick grpc-ecosystem interceptors use to thread a modified
+// context through a streaming call without grpc-go support for it
+// directly.
+type languageServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *languageServerStream) Context() context.Context {
+	return s.ctx
+}